@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CloudCoreConfig is the top-level configuration for cloudcore.
+type CloudCoreConfig struct {
+	KubeAPIConfig *KubeAPIConfig `json:"kubeAPIConfig,omitempty"`
+	Modules       *Modules       `json:"modules,omitempty"`
+}
+
+// KubeAPIConfig indicates the configuration for interacting with k8s server
+type KubeAPIConfig struct {
+	// KubeConfig indicates the path to kubeconfig file with authorization and master location information.
+	KubeConfig string `json:"kubeConfig,omitempty"`
+	// Master indicates the address of the Kubernetes API server, overrides the kubeconfig's server field.
+	Master string `json:"master,omitempty"`
+	// CAFile indicates the path to a CA bundle used to verify the Kubernetes API server's certificate,
+	// in addition to whatever is already carried by KubeConfig.
+	CAFile string `json:"caFile,omitempty"`
+	// ContentType indicates the content type of requests sent to the apiserver, default application/vnd.kubernetes.protobuf
+	ContentType string `json:"contentType,omitempty"`
+	// QPS to use while talking with kubernetes apiserver, default 100
+	QPS float32 `json:"qps,omitempty"`
+	// Burst to use while talking with kubernetes apiserver, default 200
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// Modules indicates the modules of cloudcore will be use
+type Modules struct {
+	CloudHub         *CloudHub         `json:"cloudHub,omitempty"`
+	EdgeController   *EdgeController   `json:"edgeController,omitempty"`
+	DeviceController *DeviceController `json:"deviceController,omitempty"`
+	SyncController   *SyncController   `json:"syncController,omitempty"`
+}
+
+// CloudHub indicates the config of CloudHub module
+type CloudHub struct {
+	// Enable indicates whether CloudHub is enabled, default true
+	Enable bool `json:"enable,omitempty"`
+	// TLSCAFile indicates ca file path
+	TLSCAFile string `json:"tlsCAFile,omitempty"`
+	// TLSCertFile indicates cert file path
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	// TLSPrivateKeyFile indicates key file path
+	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
+	// TLS indicates the CloudHub TLS material lifecycle settings
+	TLS CloudHubTLS `json:"tls,omitempty"`
+	// WebSocket indicates websocket server info
+	WebSocket CloudHubWebSocket `json:"webSocket,omitempty"`
+	// Quic indicates quic server info
+	Quic CloudHubQUIC `json:"quic,omitempty"`
+	// UnixSocket indicates unix domain socket info, used by edgecore and cloudcore in the same node
+	UnixSocket CloudHubUnixSocket `json:"unixSocket,omitempty"`
+}
+
+// CloudHubTLS indicates the lifecycle of the CA/cert/key material CloudHub serves.
+type CloudHubTLS struct {
+	// AutoGenerate indicates whether cloudcore should generate a self-signed CA
+	// and server certificate when TLSCAFile/TLSCertFile/TLSPrivateKeyFile are
+	// missing, instead of failing validation. Default false.
+	AutoGenerate bool `json:"autoGenerate,omitempty"`
+	// ReloadInterval indicates how often the CA/cert/key files are re-read from
+	// disk and, if changed, swapped into the running TLS config. Default 5m.
+	ReloadInterval metav1.Duration `json:"reloadInterval,omitempty"`
+}
+
+// CloudHubWebSocket indicates the websocket server info
+type CloudHubWebSocket struct {
+	// Enable indicates whether websocket server is enabled, default true.
+	// CloudHub requires at least one of WebSocket/Quic to be enabled.
+	Enable bool `json:"enable,omitempty"`
+	// Address indicates the bind address of websocket server
+	Address string `json:"address,omitempty"`
+	// Port indicates the bind port of websocket server, default 10000
+	Port uint32 `json:"port,omitempty"`
+}
+
+// CloudHubQUIC indicates the quic server info
+type CloudHubQUIC struct {
+	// Enable indicates whether quic server is enabled, default false
+	Enable bool `json:"enable,omitempty"`
+	// Address indicates the bind address of quic server
+	Address string `json:"address,omitempty"`
+	// Port indicates the bind port of quic server, default 10001
+	Port uint32 `json:"port,omitempty"`
+}
+
+// CloudHubUnixSocket indicates the unix domain socket info
+type CloudHubUnixSocket struct {
+	// Address indicates the unix domain socket address, e.g. unix:///var/lib/kubeedge/kubeedge.sock
+	Address string `json:"address,omitempty"`
+	// AllowedParentDirs restricts the directories the unix socket may be created
+	// under. When empty, a built-in default allow-list is used.
+	AllowedParentDirs []string `json:"allowedParentDirs,omitempty"`
+}
+
+// EdgeController indicates the config of EdgeController module
+type EdgeController struct {
+	// Enable indicates whether EdgeController is enabled, default true
+	Enable bool `json:"enable,omitempty"`
+	// NodeUpdateFrequency indicates node update frequency (second)
+	NodeUpdateFrequency int32 `json:"nodeUpdateFrequency,omitempty"`
+}
+
+// DeviceController indicates the config of DeviceController module
+type DeviceController struct {
+	// Enable indicates whether DeviceController is enabled, default true
+	Enable bool `json:"enable,omitempty"`
+}
+
+// SyncController indicates the config of SyncController module
+type SyncController struct {
+	// Enable indicates whether SyncController is enabled, default true
+	Enable bool `json:"enable,omitempty"`
+}
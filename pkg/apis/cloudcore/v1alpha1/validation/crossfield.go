@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	cloudconfig "github.com/kubeedge/kubeedge/pkg/apis/cloudcore/v1alpha1"
+)
+
+// defaultUnixSocketParentDirs is used when CloudHub.UnixSocket.AllowedParentDirs
+// is empty, restricting where the CloudHub unix socket may be created.
+var defaultUnixSocketParentDirs = []string{"/var/lib/kubeedge", "/var/run/kubeedge", "/run/kubeedge", "/tmp"}
+
+// maxUnixSocketPathLen is the platform limit on sockaddr_un.sun_path, including
+// the terminating NUL byte.
+func maxUnixSocketPathLen() int {
+	if runtime.GOOS == "darwin" {
+		return 104
+	}
+	return 108
+}
+
+// validateCloudHubCrossField checks invariants that span WebSocket, Quic and
+// UnixSocket rather than belonging to any single transport: that enabled
+// transports don't collide on the same bind address/port, that at least one
+// transport is enabled, and that the unix socket path is safe and short
+// enough for the platform to accept.
+func validateCloudHubCrossField(c cloudconfig.CloudHub) ValidationResults {
+	results := ValidationResults{}
+
+	if !c.WebSocket.Enable && !c.Quic.Enable {
+		results.AddErrors(field.Invalid(field.NewPath("websocket", "enable"), c.WebSocket.Enable,
+			"at least one of websocket or quic must be enabled when CloudHub is enabled"))
+	}
+	if !c.WebSocket.Enable && c.WebSocket.Address != "" {
+		results.AddWarnings(field.Invalid(field.NewPath("webSocket", "address"), c.WebSocket.Address,
+			"websocket is disabled but an address is configured; it will be ignored"))
+	}
+	if !c.Quic.Enable && c.Quic.Address != "" {
+		results.AddWarnings(field.Invalid(field.NewPath("quic", "address"), c.Quic.Address,
+			"quic is disabled but an address is configured; it will be ignored"))
+	}
+
+	if c.WebSocket.Enable && c.Quic.Enable && addrPortCollide(c.WebSocket.Address, c.WebSocket.Port, c.Quic.Address, c.Quic.Port) {
+		results.AddErrors(field.Invalid(field.NewPath("quic", "address"), c.Quic.Address,
+			fmt.Sprintf("quic and websocket cannot both bind %s:%d", c.Quic.Address, c.Quic.Port)))
+	}
+
+	s := strings.SplitN(c.UnixSocket.Address, "://", 2)
+	if len(s) > 1 {
+		socketPath := s[1]
+		if len(socketPath)+1 > maxUnixSocketPathLen() {
+			results.AddErrors(field.Invalid(field.NewPath("address"), c.UnixSocket.Address,
+				fmt.Sprintf("unix socket path %q is %d bytes, which with its terminating NUL exceeds the %d byte sun_path limit on %s",
+					socketPath, len(socketPath), maxUnixSocketPathLen(), runtime.GOOS)))
+		}
+		if !isUnderAllowedParentDir(socketPath, allowedUnixSocketParentDirs(c)) {
+			results.AddErrors(field.Invalid(field.NewPath("address"), c.UnixSocket.Address,
+				fmt.Sprintf("unix socket path %q is not under an allowed parent directory %v",
+					socketPath, allowedUnixSocketParentDirs(c))))
+		}
+	}
+
+	return results
+}
+
+// addrPortCollide reports whether two transports bind the identical
+// (address, port) pair. A wildcard address (empty, 0.0.0.0 or ::) is skipped,
+// since it binds every interface and can't be proven to collide or not
+// against a single specific address.
+func addrPortCollide(addrA string, portA uint32, addrB string, portB uint32) bool {
+	if isWildcardAddr(addrA) || isWildcardAddr(addrB) {
+		return false
+	}
+	return addrA == addrB && portA == portB
+}
+
+func isWildcardAddr(address string) bool {
+	return address == "" || address == "0.0.0.0" || address == "::"
+}
+
+func allowedUnixSocketParentDirs(c cloudconfig.CloudHub) []string {
+	if len(c.UnixSocket.AllowedParentDirs) > 0 {
+		return c.UnixSocket.AllowedParentDirs
+	}
+	return defaultUnixSocketParentDirs
+}
+
+func isUnderAllowedParentDir(socketPath string, allowed []string) bool {
+	dir := filepath.Clean(filepath.Dir(socketPath))
+	for _, parent := range allowed {
+		parent = filepath.Clean(parent)
+		if dir == parent || strings.HasPrefix(dir, parent+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
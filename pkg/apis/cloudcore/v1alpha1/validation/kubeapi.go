@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	cloudconfig "github.com/kubeedge/kubeedge/pkg/apis/cloudcore/v1alpha1"
+	utilvalidation "github.com/kubeedge/kubeedge/pkg/util/validation"
+)
+
+// inClusterTokenFile and inClusterCAFile are the paths a pod's serviceaccount
+// volume mounts, mirroring the probe client-go's InClusterConfig does before
+// falling back to KubeConfig.
+const (
+	inClusterTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// allowedContentTypes are the client-go request content types CloudCore supports.
+var allowedContentTypes = map[string]bool{
+	"application/json":                    true,
+	"application/vnd.kubernetes.protobuf": true,
+}
+
+// ValidateKubeAPIConfig validates `k` and returns the accumulated warnings and errors.
+func ValidateKubeAPIConfig(k cloudconfig.KubeAPIConfig) ValidationResults {
+	results := ValidationResults{}
+
+	if k.KubeConfig == "" {
+		if !hasInClusterCredentials() {
+			results.AddWarnings(field.Invalid(field.NewPath("kubeconfig"), k.KubeConfig,
+				"kubeconfig is empty and in-cluster serviceaccount credentials were not found; API calls will fail"))
+		}
+	} else {
+		if !path.IsAbs(k.KubeConfig) {
+			results.AddErrors(field.Invalid(field.NewPath("kubeconfig"), k.KubeConfig, "kubeconfig need abs path"))
+		}
+		if !utilvalidation.FileIsExist(k.KubeConfig) {
+			results.AddErrors(field.Invalid(field.NewPath("kubeconfig"), k.KubeConfig, "kubeconfig not exist"))
+		}
+	}
+
+	if k.Master != "" {
+		if u, err := url.Parse(k.Master); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			results.AddErrors(field.Invalid(field.NewPath("master"), k.Master, "master must be a parseable http or https URL"))
+		}
+	}
+
+	if k.QPS <= 0 {
+		results.AddErrors(field.Invalid(field.NewPath("qps"), k.QPS, "qps need > 0"))
+	}
+	if float32(k.Burst) < k.QPS {
+		results.AddErrors(field.Invalid(field.NewPath("burst"), k.Burst, "burst need >= qps"))
+	}
+
+	if k.ContentType != "" && !allowedContentTypes[k.ContentType] {
+		results.AddErrors(field.Invalid(field.NewPath("contentType"), k.ContentType,
+			"contentType must be application/json or application/vnd.kubernetes.protobuf"))
+	}
+
+	if k.CAFile != "" {
+		if err := validateCAFile(k.CAFile); err != nil {
+			results.AddErrors(field.Invalid(field.NewPath("caFile"), k.CAFile, err.Error()))
+		}
+	}
+
+	return results
+}
+
+// hasInClusterCredentials reports whether rest.InClusterConfig() would
+// succeed: the KUBERNETES_SERVICE_HOST/PORT env vars it gates on are set, and
+// the serviceaccount token and CA it then reads are both present.
+func hasInClusterCredentials() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" || os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+	return utilvalidation.FileIsExist(inClusterTokenFile) && utilvalidation.FileIsExist(inClusterCAFile)
+}
+
+// validateCAFile loads caFile and confirms it parses as a PEM certificate bundle.
+func validateCAFile(caFile string) error {
+	pemBytes, err := os.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("failed to read caFile: %w", err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(pemBytes) {
+		return fmt.Errorf("failed to parse any certificates from caFile")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidationResults holds the outcome of validating a piece of configuration,
+// split into Errors that must stop cloudcore from starting and Warnings that
+// are surfaced to the operator but do not block startup. The cloudcore
+// command entrypoint is expected to print Warnings to stderr and only exit
+// non-zero on Errors; that entrypoint does not exist in this tree and is not
+// wired up here.
+type ValidationResults struct {
+	Warnings field.ErrorList
+	Errors   field.ErrorList
+}
+
+// AddErrors appends errs to the result's Errors.
+func (r *ValidationResults) AddErrors(errs ...*field.Error) {
+	r.Errors = append(r.Errors, errs...)
+}
+
+// AddWarnings appends warnings to the result's Warnings.
+func (r *ValidationResults) AddWarnings(warnings ...*field.Error) {
+	r.Warnings = append(r.Warnings, warnings...)
+}
+
+// Append merges other into r.
+func (r *ValidationResults) Append(other ValidationResults) {
+	r.Errors = append(r.Errors, other.Errors...)
+	r.Warnings = append(r.Warnings, other.Warnings...)
+}
+
+// ToAggregate returns the accumulated errors, ignoring warnings, for callers
+// that only care whether the configuration is fatally invalid.
+func (r ValidationResults) ToAggregate() field.ErrorList {
+	return r.Errors
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+	keyutil "k8s.io/client-go/util/keyutil"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	cloudconfig "github.com/kubeedge/kubeedge/pkg/apis/cloudcore/v1alpha1"
+	utilvalidation "github.com/kubeedge/kubeedge/pkg/util/validation"
+)
+
+// certExpiryWarnWindow is how far ahead of a server certificate's NotAfter
+// operators are warned that it needs rotating.
+const certExpiryWarnWindow = 30 * 24 * time.Hour
+
+// validateCloudHubTLS checks that TLSCAFile, TLSCertFile and TLSPrivateKeyFile exist,
+// parse, and are usable together. When the files are missing and
+// CloudHub.TLS.AutoGenerate is set, it generates a self-signed CA and server
+// certificate into the configured paths instead of failing, analogous to the
+// kubelet InitializeTLS fallback for a zero-config dev setup.
+func validateCloudHubTLS(c cloudconfig.CloudHub) ValidationResults {
+	results := ValidationResults{}
+
+	caExists := utilvalidation.FileIsExist(c.TLSCAFile)
+	certExists := utilvalidation.FileIsExist(c.TLSCertFile)
+	keyExists := utilvalidation.FileIsExist(c.TLSPrivateKeyFile)
+
+	if !caExists || !certExists || !keyExists {
+		if !c.TLS.AutoGenerate {
+			if !caExists {
+				results.AddErrors(field.Invalid(field.NewPath("TLSCAFile"), c.TLSCAFile, "TLSCAFile not exist"))
+			}
+			if !certExists {
+				results.AddErrors(field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile, "TLSCertFile not exist"))
+			}
+			if !keyExists {
+				results.AddErrors(field.Invalid(field.NewPath("TLSPrivateKeyFile"), c.TLSPrivateKeyFile, "TLSPrivateKeyFile not exist"))
+			}
+			return results
+		}
+		if caExists || certExists || keyExists {
+			// Any one of the three files already existing means there is real
+			// material on disk we can't safely regenerate around: we don't sign a
+			// new leaf with an existing CA's private key (it is not one of the
+			// configured paths), and overwriting an existing cert/key
+			// pair would silently destroy it. Only auto-generate a brand new
+			// self-signed CA when all three paths are empty.
+			results.AddErrors(field.Invalid(field.NewPath("TLS", "AutoGenerate"), true,
+				fmt.Sprintf("one or more of TLSCAFile %s, TLSCertFile %s, TLSPrivateKeyFile %s already exist "+
+					"while the others are missing; provide a complete cert/key pair signed by the existing CA, "+
+					"or remove the existing file(s) to let a fresh self-signed CA be generated",
+					c.TLSCAFile, c.TLSCertFile, c.TLSPrivateKeyFile)))
+			return results
+		}
+		if err := generateSelfSignedCloudHubTLS(c); err != nil {
+			results.AddErrors(field.Invalid(field.NewPath("TLS", "AutoGenerate"), true,
+				fmt.Sprintf("failed to auto-generate TLS material: %v", err)))
+			return results
+		}
+	}
+
+	caPool, err := loadCAPool(c.TLSCAFile)
+	if err != nil {
+		results.AddErrors(field.Invalid(field.NewPath("TLSCAFile"), c.TLSCAFile, err.Error()))
+	}
+
+	pair, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSPrivateKeyFile)
+	if err != nil {
+		results.AddErrors(field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile,
+			fmt.Sprintf("failed to load cert/key pair: %v", err)))
+		return results
+	}
+
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		results.AddErrors(field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile,
+			fmt.Sprintf("failed to parse server certificate: %v", err)))
+		return results
+	}
+
+	now := time.Now()
+	switch {
+	case now.Before(leaf.NotBefore):
+		results.AddErrors(field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile,
+			fmt.Sprintf("certificate is not valid until %v", leaf.NotBefore)))
+	case now.After(leaf.NotAfter):
+		results.AddErrors(field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile,
+			fmt.Sprintf("certificate expired at %v", leaf.NotAfter)))
+	case now.Add(certExpiryWarnWindow).After(leaf.NotAfter):
+		results.AddWarnings(field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile,
+			fmt.Sprintf("certificate expires at %v, renew it soon", leaf.NotAfter)))
+	}
+
+	for _, host := range cloudHubBindHosts(c) {
+		if err := leaf.VerifyHostname(host); err != nil {
+			results.AddErrors(field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile,
+				fmt.Sprintf("certificate does not cover %q: %v", host, err)))
+		}
+	}
+
+	if caPool != nil {
+		if _, err := leaf.Verify(x509.VerifyOptions{Roots: caPool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+			results.AddErrors(field.Invalid(field.NewPath("TLSCAFile"), c.TLSCAFile,
+				fmt.Sprintf("server certificate does not chain to CA: %v", err)))
+		}
+	}
+
+	return results
+}
+
+// loadCAPool reads caFile and parses it into an x509.CertPool.
+func loadCAPool(caFile string) (*x509.CertPool, error) {
+	pemBytes, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("failed to parse any certificates from %s", caFile)
+	}
+	return pool, nil
+}
+
+// cloudHubBindHosts returns the addresses the CloudHub server certificate must
+// cover. Wildcard addresses (empty, 0.0.0.0, ::) are skipped: they bind every
+// interface rather than naming one, so no real certificate will ever carry
+// them as a SAN.
+func cloudHubBindHosts(c cloudconfig.CloudHub) []string {
+	var hosts []string
+	if !isWildcardAddr(c.WebSocket.Address) {
+		hosts = append(hosts, c.WebSocket.Address)
+	}
+	if !isWildcardAddr(c.Quic.Address) && c.Quic.Address != c.WebSocket.Address {
+		hosts = append(hosts, c.Quic.Address)
+	}
+	return hosts
+}
+
+// generateSelfSignedCloudHubTLS creates a self-signed CA and a server certificate
+// signed by it, covering the CloudHub bind addresses and the local hostname, and
+// writes them to the paths configured in c.
+func generateSelfSignedCloudHubTLS(c cloudconfig.CloudHub) error {
+	altNames := certutil.AltNames{}
+	hosts := cloudHubBindHosts(c)
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hosts = append(hosts, hostname)
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			altNames.IPs = append(altNames.IPs, ip)
+		} else {
+			altNames.DNSNames = append(altNames.DNSNames, host)
+		}
+	}
+
+	caKey, err := certutil.NewPrivateKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate CA private key: %w", err)
+	}
+	caCert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "kubeedge-cloudhub-ca"}, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to generate self-signed CA cert: %w", err)
+	}
+
+	serverKey, err := certutil.NewPrivateKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate server private key: %w", err)
+	}
+	serverCert, err := certutil.NewSignedCert(certutil.Config{
+		CommonName: "cloudhub",
+		AltNames:   altNames,
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, serverKey, caCert, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign server cert: %w", err)
+	}
+
+	serverKeyPEM, err := keyutil.MarshalPrivateKeyToPEM(serverKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode server key: %w", err)
+	}
+	caKeyPEM, err := keyutil.MarshalPrivateKeyToPEM(caKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode CA key: %w", err)
+	}
+
+	if err := writeGeneratedPEM(c.TLSCAFile, certutil.EncodeCertPEM(caCert), 0644); err != nil {
+		return err
+	}
+	if err := writeGeneratedPEM(cloudHubCAKeyFile(c.TLSCAFile), caKeyPEM, 0600); err != nil {
+		return err
+	}
+	if err := writeGeneratedPEM(c.TLSCertFile, certutil.EncodeCertPEM(serverCert), 0644); err != nil {
+		return err
+	}
+	if err := writeGeneratedPEM(c.TLSPrivateKeyFile, serverKeyPEM, 0600); err != nil {
+		return err
+	}
+	return nil
+}
+
+// cloudHubCAKeyFile derives the CA private key path from the CA certificate path,
+// e.g. rootCA.crt -> rootCA.key.
+func cloudHubCAKeyFile(caFile string) string {
+	ext := filepath.Ext(caFile)
+	return strings.TrimSuffix(caFile, ext) + ".key"
+}
+
+// writeGeneratedPEM creates the parent directory of path if needed and writes data
+// to it with the given permissions.
+func writeGeneratedPEM(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
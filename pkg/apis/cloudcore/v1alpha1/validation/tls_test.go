@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cloudconfig "github.com/kubeedge/kubeedge/pkg/apis/cloudcore/v1alpha1"
+)
+
+func testCloudHub(dir string) cloudconfig.CloudHub {
+	return cloudconfig.CloudHub{
+		Enable:            true,
+		TLSCAFile:         filepath.Join(dir, "rootCA.crt"),
+		TLSCertFile:       filepath.Join(dir, "server.crt"),
+		TLSPrivateKeyFile: filepath.Join(dir, "server.key"),
+		WebSocket:         cloudconfig.CloudHubWebSocket{Enable: true, Address: "127.0.0.1", Port: 10000},
+	}
+}
+
+func TestGenerateSelfSignedCloudHubTLS(t *testing.T) {
+	c := testCloudHub(t.TempDir())
+
+	if err := generateSelfSignedCloudHubTLS(c); err != nil {
+		t.Fatalf("generateSelfSignedCloudHubTLS() error = %v", err)
+	}
+	for _, f := range []string{c.TLSCAFile, c.TLSCertFile, c.TLSPrivateKeyFile} {
+		if _, err := os.Stat(f); err != nil {
+			t.Fatalf("expected %s to exist: %v", f, err)
+		}
+	}
+
+	results := validateCloudHubTLS(c)
+	if len(results.Errors) != 0 {
+		t.Fatalf("validateCloudHubTLS() after auto-generate unexpected errors: %v", results.Errors)
+	}
+}
+
+func TestValidateCloudHubTLSAllowsWildcardBindAddress(t *testing.T) {
+	dir := t.TempDir()
+	c := testCloudHub(dir)
+	c.WebSocket.Address = "0.0.0.0"
+
+	writeSelfSignedCertAndKey(t, c.TLSCertFile, c.TLSPrivateKeyFile, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	copyFile(t, c.TLSCertFile, c.TLSCAFile)
+
+	results := validateCloudHubTLS(c)
+	if len(results.Errors) != 0 {
+		t.Fatalf("validateCloudHubTLS() with a 0.0.0.0 bind address unexpected errors: %v", results.Errors)
+	}
+}
+
+func TestValidateCloudHubTLSRefusesToReplaceExistingCA(t *testing.T) {
+	dir := t.TempDir()
+	c := testCloudHub(dir)
+	c.TLS.AutoGenerate = true
+
+	writeSelfSignedCertAndKey(t, c.TLSCAFile, filepath.Join(dir, "unused.key"), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	// TLSCertFile/TLSPrivateKeyFile are deliberately left missing.
+
+	results := validateCloudHubTLS(c)
+	if len(results.Errors) == 0 {
+		t.Fatal("validateCloudHubTLS() expected an error when CA exists but cert/key are missing, got none")
+	}
+	if _, err := os.Stat(c.TLSCertFile); err == nil {
+		t.Fatal("validateCloudHubTLS() must not generate a server cert without the operator's CA key")
+	}
+}
+
+func TestValidateCloudHubTLSRefusesToReplaceExistingCertKey(t *testing.T) {
+	dir := t.TempDir()
+	c := testCloudHub(dir)
+	c.TLS.AutoGenerate = true
+
+	writeSelfSignedCertAndKey(t, c.TLSCertFile, c.TLSPrivateKeyFile, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	// TLSCAFile is deliberately left missing.
+
+	results := validateCloudHubTLS(c)
+	if len(results.Errors) == 0 {
+		t.Fatal("validateCloudHubTLS() expected an error when cert/key exist but CA is missing, got none")
+	}
+	if _, err := os.Stat(c.TLSCAFile); err == nil {
+		t.Fatal("validateCloudHubTLS() must not generate a CA that doesn't match the operator's existing cert/key")
+	}
+}
+
+func TestValidateCloudHubTLSExpired(t *testing.T) {
+	dir := t.TempDir()
+	c := testCloudHub(dir)
+
+	writeSelfSignedCertAndKey(t, c.TLSCertFile, c.TLSPrivateKeyFile, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+	copyFile(t, c.TLSCertFile, c.TLSCAFile)
+
+	results := validateCloudHubTLS(c)
+	if len(results.Errors) == 0 {
+		t.Fatal("validateCloudHubTLS() expected an error for an expired certificate, got none")
+	}
+}
+
+// writeSelfSignedCertAndKey generates a fresh key and a self-signed
+// certificate valid between notBefore and notAfter, writing the certificate
+// to certPath and the key to keyPath.
+func writeSelfSignedCertAndKey(t *testing.T, certPath, keyPath string, notBefore, notAfter time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create test certificate: %v", err)
+	}
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("failed to write test certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal test key: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("failed to write test key: %v", err)
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", src, err)
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", dst, err)
+	}
+}
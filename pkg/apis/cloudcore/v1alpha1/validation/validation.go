@@ -28,113 +28,94 @@ import (
 	utilvalidation "github.com/kubeedge/kubeedge/pkg/util/validation"
 )
 
-// ValidateCloudCoreConfiguration validates `c` and returns an errorList if it is invalid
-func ValidateCloudCoreConfiguration(c *cloudconfig.CloudCoreConfig) field.ErrorList {
-	allErrs := field.ErrorList{}
-	allErrs = append(allErrs, ValidateKubeAPIConfig(*c.KubeAPIConfig)...)
-	allErrs = append(allErrs, ValidateModuleCloudHub(*c.Modules.CloudHub)...)
-	allErrs = append(allErrs, ValidateModuleEdgeController(*c.Modules.EdgeController)...)
-	allErrs = append(allErrs, ValidateModuleDeviceController(*c.Modules.DeviceController)...)
-	allErrs = append(allErrs, ValidateModuleSyncController(*c.Modules.SyncController)...)
-	return allErrs
+// lowNodeUpdateFrequency is the threshold below which NodeUpdateFrequency is
+// warned about as likely to overload the API server with status updates.
+const lowNodeUpdateFrequency = 5
+
+// ValidateCloudCoreConfiguration validates `c` and returns the accumulated
+// warnings and errors.
+func ValidateCloudCoreConfiguration(c *cloudconfig.CloudCoreConfig) ValidationResults {
+	results := ValidationResults{}
+	results.Append(ValidateKubeAPIConfig(*c.KubeAPIConfig))
+	results.Append(ValidateModuleCloudHub(*c.Modules.CloudHub))
+	results.Append(ValidateModuleEdgeController(*c.Modules.EdgeController))
+	results.Append(ValidateModuleDeviceController(*c.Modules.DeviceController))
+	results.Append(ValidateModuleSyncController(*c.Modules.SyncController))
+	return results
 }
 
-// ValidateModuleCloudHub validates `c` and returns an errorList if it is invalid
-func ValidateModuleCloudHub(c cloudconfig.CloudHub) field.ErrorList {
+// ValidateModuleCloudHub validates `c` and returns the accumulated warnings and errors.
+func ValidateModuleCloudHub(c cloudconfig.CloudHub) ValidationResults {
 	if !c.Enable {
-		return field.ErrorList{}
+		return ValidationResults{}
 	}
 
-	allErrs := field.ErrorList{}
-	validWPort := utilvalidation.IsValidPortNum(int(c.WebSocket.Port))
-	validAddress := utilvalidation.IsValidIP(c.WebSocket.Address)
-	validQPort := utilvalidation.IsValidPortNum(int(c.Quic.Port))
-	validQAddress := utilvalidation.IsValidIP(c.Quic.Address)
-
-	if len(validWPort) > 0 {
-		for _, m := range validWPort {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("port"), c.WebSocket.Port, m))
+	results := ValidationResults{}
+	if c.WebSocket.Enable {
+		for _, m := range utilvalidation.IsValidPortNum(int(c.WebSocket.Port)) {
+			results.AddErrors(field.Invalid(field.NewPath("port"), c.WebSocket.Port, m))
 		}
-	}
-	if len(validAddress) > 0 {
-		for _, m := range validAddress {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("Address"), c.WebSocket.Address, m))
+		for _, m := range utilvalidation.IsValidIP(c.WebSocket.Address) {
+			results.AddErrors(field.Invalid(field.NewPath("Address"), c.WebSocket.Address, m))
 		}
 	}
-	if len(validQPort) > 0 {
-		for _, m := range validQPort {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("port"), c.Quic.Port, m))
+	if c.Quic.Enable {
+		for _, m := range utilvalidation.IsValidPortNum(int(c.Quic.Port)) {
+			results.AddErrors(field.Invalid(field.NewPath("port"), c.Quic.Port, m))
 		}
-	}
-	if len(validQAddress) > 0 {
-		for _, m := range validQAddress {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("Address"), c.Quic.Address, m))
+		for _, m := range utilvalidation.IsValidIP(c.Quic.Address) {
+			results.AddErrors(field.Invalid(field.NewPath("Address"), c.Quic.Address, m))
 		}
 	}
-	if !utilvalidation.FileIsExist(c.TLSPrivateKeyFile) {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("TLSPrivateKeyFile"), c.TLSPrivateKeyFile, "TLSPrivateKeyFile not exist"))
-	}
-	if !utilvalidation.FileIsExist(c.TLSCertFile) {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("TLSCertFile"), c.TLSCertFile, "TLSCertFile not exist"))
-	}
-	if !utilvalidation.FileIsExist(c.TLSCAFile) {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("TLSCAFile"), c.TLSCAFile, "TLSCAFile not exist"))
-	}
+	results.Append(validateCloudHubTLS(c))
 	if !strings.HasPrefix(strings.ToLower(c.UnixSocket.Address), "unix://") {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("address"),
+		results.AddErrors(field.Invalid(field.NewPath("address"),
 			c.UnixSocket.Address, "unixSocketAddress must has prefix unix://"))
 	}
 	s := strings.SplitN(c.UnixSocket.Address, "://", 2)
 	if len(s) > 1 && !utilvalidation.FileIsExist(path.Dir(s[1])) {
 		if err := os.MkdirAll(path.Dir(s[1]), os.ModePerm); err != nil {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("address"),
+			results.AddErrors(field.Invalid(field.NewPath("address"),
 				c.UnixSocket.Address, fmt.Sprintf("create unixSocketAddress %v dir %v error: %v",
 					c.UnixSocket.Address, path.Dir(s[1]), err)))
+		} else {
+			results.AddWarnings(field.Invalid(field.NewPath("address"), c.UnixSocket.Address,
+				fmt.Sprintf("unixSocketAddress dir %v did not exist and was created", path.Dir(s[1]))))
 		}
 	}
-	return allErrs
+	results.Append(validateCloudHubCrossField(c))
+	return results
 }
 
-// ValidateModuleEdgeController validates `e` and returns an errorList if it is invalid
-func ValidateModuleEdgeController(e cloudconfig.EdgeController) field.ErrorList {
+// ValidateModuleEdgeController validates `e` and returns the accumulated warnings and errors.
+func ValidateModuleEdgeController(e cloudconfig.EdgeController) ValidationResults {
 	if !e.Enable {
-		return field.ErrorList{}
+		return ValidationResults{}
 	}
-	allErrs := field.ErrorList{}
+	results := ValidationResults{}
 	if e.NodeUpdateFrequency <= 0 {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("NodeUpdateFrequency"), e.NodeUpdateFrequency, "NodeUpdateFrequency need > 0"))
+		results.AddErrors(field.Invalid(field.NewPath("NodeUpdateFrequency"), e.NodeUpdateFrequency, "NodeUpdateFrequency need > 0"))
+	} else if e.NodeUpdateFrequency < lowNodeUpdateFrequency {
+		results.AddWarnings(field.Invalid(field.NewPath("NodeUpdateFrequency"), e.NodeUpdateFrequency,
+			fmt.Sprintf("NodeUpdateFrequency below %ds may overload the API server with status updates", lowNodeUpdateFrequency)))
 	}
-	return allErrs
+	return results
 }
 
-// ValidateModuleDeviceController validates `d` and returns an errorList if it is invalid
-func ValidateModuleDeviceController(d cloudconfig.DeviceController) field.ErrorList {
+// ValidateModuleDeviceController validates `d` and returns the accumulated warnings and errors.
+func ValidateModuleDeviceController(d cloudconfig.DeviceController) ValidationResults {
 	if !d.Enable {
-		return field.ErrorList{}
+		return ValidationResults{}
 	}
 
-	allErrs := field.ErrorList{}
-	return allErrs
+	return ValidationResults{}
 }
 
-// ValidateModuleSyncController validates `d` and returns an errorList if it is invalid
-func ValidateModuleSyncController(d cloudconfig.SyncController) field.ErrorList {
+// ValidateModuleSyncController validates `d` and returns the accumulated warnings and errors.
+func ValidateModuleSyncController(d cloudconfig.SyncController) ValidationResults {
 	if !d.Enable {
-		return field.ErrorList{}
+		return ValidationResults{}
 	}
 
-	allErrs := field.ErrorList{}
-	return allErrs
-}
-
-// ValidateKubeAPIConfig validates `k` and returns an errorList if it is invalid
-func ValidateKubeAPIConfig(k cloudconfig.KubeAPIConfig) field.ErrorList {
-	allErrs := field.ErrorList{}
-	if k.KubeConfig != "" && !path.IsAbs(k.KubeConfig) {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("kubeconfig"), k.KubeConfig, "kubeconfig need abs path"))
-	}
-	if k.KubeConfig != "" && !utilvalidation.FileIsExist(k.KubeConfig) {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("kubeconfig"), k.KubeConfig, "kubeconfig not exist"))
-	}
-	return allErrs
+	return ValidationResults{}
 }
@@ -0,0 +1,215 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tlsreloader periodically re-reads CloudHub's CA/cert/key files
+// from disk and atomically swaps the *tls.Config served to the WebSocket and
+// QUIC listeners whenever the material on disk changes. ValidateModuleCloudHub
+// only checks these files once at process start; without this, a certificate
+// rotated on disk after that (e.g. by a Vault kubernetes-auth renewal) never
+// reaches the running server, and edge nodes start failing or succeeding TLS
+// handshakes against stale trust.
+package tlsreloader
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	cloudconfig "github.com/kubeedge/kubeedge/pkg/apis/cloudcore/v1alpha1"
+)
+
+// DefaultReloadInterval is how often the reloader re-reads the CA/cert/key
+// files from disk when not overridden.
+const DefaultReloadInterval = 5 * time.Minute
+
+// Reloader holds the most recently loaded CloudHub CA pool and server
+// certificate, and refreshes them from disk on a timer.
+type Reloader struct {
+	caFile   string
+	certFile string
+	keyFile  string
+	interval time.Duration
+	onRotate func(reloaded bool)
+
+	mu       sync.RWMutex
+	certPool *x509.CertPool
+	cert     *tls.Certificate
+	caHash   [sha256.Size]byte
+	certHash [sha256.Size]byte
+
+	rotations int64
+}
+
+// Option customizes a Reloader returned by NewReloader.
+type Option func(*Reloader)
+
+// WithInterval overrides DefaultReloadInterval.
+func WithInterval(interval time.Duration) Option {
+	return func(r *Reloader) {
+		if interval > 0 {
+			r.interval = interval
+		}
+	}
+}
+
+// WithRotationHook registers a callback invoked after every reload attempt
+// that did not error; reloaded is true only when the served CA pool or leaf
+// certificate actually changed. Use it to log rotations and bump metrics.
+func WithRotationHook(hook func(reloaded bool)) Option {
+	return func(r *Reloader) {
+		r.onRotate = hook
+	}
+}
+
+// NewReloader builds a Reloader for the given CA/cert/key paths and performs
+// an initial synchronous load, so callers get a populated TLS config
+// immediately instead of only after the first tick.
+func NewReloader(caFile, certFile, keyFile string, opts ...Option) (*Reloader, error) {
+	r := &Reloader{
+		caFile:   caFile,
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: DefaultReloadInterval,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewReloaderFromCloudHubConfig builds a Reloader for CloudHub's configured
+// TLSCAFile/TLSCertFile/TLSPrivateKeyFile, honoring CloudHub.TLS.ReloadInterval
+// when it is set.
+func NewReloaderFromCloudHubConfig(c cloudconfig.CloudHub, opts ...Option) (*Reloader, error) {
+	if c.TLS.ReloadInterval.Duration > 0 {
+		opts = append([]Option{WithInterval(c.TLS.ReloadInterval.Duration)}, opts...)
+	}
+	return NewReloader(c.TLSCAFile, c.TLSCertFile, c.TLSPrivateKeyFile, opts...)
+}
+
+// Run starts the periodic reload loop and blocks until stopCh is closed.
+// Reload errors are logged and otherwise ignored: the Reloader keeps serving
+// the last good TLS material rather than taking the process down.
+func (r *Reloader) Run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.reload(); err != nil {
+				klog.Errorf("tlsreloader: failed to reload CloudHub TLS material, keeping last good config: %v", err)
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// GetCertificate implements tls.Config.GetCertificate, always serving the
+// most recently loaded server certificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil {
+		return nil, fmt.Errorf("tlsreloader: no certificate loaded yet")
+	}
+	return r.cert, nil
+}
+
+// GetConfigForClient implements tls.Config.GetConfigForClient, serving a
+// *tls.Config built from the most recently loaded CA pool and certificate.
+func (r *Reloader) GetConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil {
+		return nil, fmt.Errorf("tlsreloader: no certificate loaded yet")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{*r.cert},
+		ClientCAs:    r.certPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// Rotations returns how many times the served TLS material has been loaded
+// or actually changed, counting the initial load in NewReloader as the first,
+// suitable for exposing as a metric.
+func (r *Reloader) Rotations() int64 {
+	return atomic.LoadInt64(&r.rotations)
+}
+
+func (r *Reloader) reload() error {
+	caPEM, err := os.ReadFile(r.caFile)
+	if err != nil {
+		return fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("failed to parse any certificates from %s", r.caFile)
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load cert/key pair: %w", err)
+	}
+
+	// Hash the decoded CA certificates rather than the file bytes, so edits
+	// that leave the certificates themselves unchanged are not a rotation.
+	caDigest := sha256.New()
+	for rest := caPEM; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			caDigest.Write(block.Bytes)
+		}
+	}
+	var caHash [sha256.Size]byte
+	copy(caHash[:], caDigest.Sum(nil))
+	certHash := sha256.Sum256(cert.Certificate[0])
+
+	r.mu.Lock()
+	changed := r.cert == nil || caHash != r.caHash || certHash != r.certHash
+	if changed {
+		r.certPool = pool
+		r.cert = &cert
+		r.caHash = caHash
+		r.certHash = certHash
+		atomic.AddInt64(&r.rotations, 1)
+	}
+	r.mu.Unlock()
+
+	if changed {
+		klog.Infof("tlsreloader: reloaded CloudHub TLS material from %s and %s", r.caFile, r.certFile)
+	}
+	if r.onRotate != nil {
+		r.onRotate(changed)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsreloader
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestCertAndKey(t *testing.T, certPath, keyPath string, serial int64) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create test certificate: %v", err)
+	}
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("failed to write test certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal test key: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("failed to write test key: %v", err)
+	}
+}
+
+func TestReloaderReloadDetectsChange(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "rootCA.crt")
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+
+	writeTestCertAndKey(t, caFile, filepath.Join(dir, "rootCA.key"), 1)
+	writeTestCertAndKey(t, certFile, keyFile, 2)
+
+	var rotations []bool
+	r, err := NewReloader(caFile, certFile, keyFile, WithRotationHook(func(reloaded bool) {
+		rotations = append(rotations, reloaded)
+	}))
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	if r.Rotations() != 1 {
+		t.Fatalf("Rotations() after initial load = %d, want 1", r.Rotations())
+	}
+	if len(rotations) != 1 || !rotations[0] {
+		t.Fatalf("rotation hook after initial load = %v, want [true]", rotations)
+	}
+
+	if err := r.reload(); err != nil {
+		t.Fatalf("reload() with unchanged files error = %v", err)
+	}
+	if r.Rotations() != 1 {
+		t.Fatalf("Rotations() after unchanged reload = %d, want 1", r.Rotations())
+	}
+
+	writeTestCertAndKey(t, certFile, keyFile, 3)
+	if err := r.reload(); err != nil {
+		t.Fatalf("reload() after cert rotation error = %v", err)
+	}
+	if r.Rotations() != 2 {
+		t.Fatalf("Rotations() after cert rotation = %d, want 2", r.Rotations())
+	}
+	if len(rotations) != 3 || !rotations[0] || rotations[1] || !rotations[2] {
+		t.Fatalf("rotation hook after cert rotation = %v, want [true, false, true]", rotations)
+	}
+}
+
+func TestReloaderGetCertificate(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "rootCA.crt")
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	writeTestCertAndKey(t, caFile, filepath.Join(dir, "rootCA.key"), 1)
+	writeTestCertAndKey(t, certFile, keyFile, 2)
+
+	r, err := NewReloader(caFile, certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate() error = %v", err)
+	}
+	if cert == nil || len(cert.Certificate) == 0 {
+		t.Fatal("GetCertificate() returned an empty certificate")
+	}
+}
+
+func TestNewReloaderMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewReloader(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key"), filepath.Join(dir, "missing2.key")); err == nil {
+		t.Fatal("NewReloader() with missing files expected an error, got none")
+	}
+}